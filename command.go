@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CommandHandler is invoked once a command (and any nested command) has been matched and its
+// flags parsed. ctx is a Parser carrying the command's own registered flags merged with those
+// of every enclosing command/root Parser.
+type CommandHandler func(ctx *Parser) error
+
+// commandSet is embedded in both Parser and Command to give each a registry of subcommands,
+// aliases, a default command and a not-found hook, so AddCommand/SetDefaultCommand/
+// OnCommandNotFound are available at the root and at any nesting depth. Only the root's
+// Parser.Dispatch consults the default command and the not-found hook; below the root,
+// Command.dispatch matches nested command names and aliases only and treats any other token
+// as a positional argument of the current command.
+type commandSet struct {
+	commands map[string]*Command
+	order    []string
+	aliases  map[string]string // alias -> command name
+
+	defaultCommand string
+	notFound       func(name string, args []string) error
+}
+
+// AddCommand registers a subcommand, optionally under one or more aliases.
+func (c *commandSet) AddCommand(name, help string, handler CommandHandler, aliases ...string) *Command {
+	cmd := &Command{Name: name, Help: help, Aliases: aliases, handler: handler, Flags: NewParser()}
+	c.addCommand(cmd)
+	return cmd
+}
+
+// SetDefaultCommand names the command to dispatch to when no command token is found in args.
+func (c *commandSet) SetDefaultCommand(name string) {
+	c.defaultCommand = name
+}
+
+// OnCommandNotFound registers a hook invoked when a command token doesn't match any registered
+// command or alias. If unset, Dispatch returns an error instead.
+func (c *commandSet) OnCommandNotFound(fn func(name string, args []string) error) {
+	c.notFound = fn
+}
+
+func (c *commandSet) addCommand(cmd *Command) {
+	if c.commands == nil {
+		c.commands = map[string]*Command{}
+		c.aliases = map[string]string{}
+	}
+
+	c.commands[cmd.Name] = cmd
+	c.order = append(c.order, cmd.Name)
+
+	for _, alias := range cmd.Aliases {
+		c.aliases[alias] = cmd.Name
+	}
+}
+
+// resolveCommand looks a token up against registered command names and aliases.
+func (c *commandSet) resolveCommand(token string) (*Command, bool) {
+	if cmd, ok := c.commands[token]; ok {
+		return cmd, true
+	}
+
+	if name, ok := c.aliases[token]; ok {
+		return c.commands[name], true
+	}
+
+	return nil, false
+}
+
+// Command is a named, optionally nested, subcommand registered via Parser.AddCommand or
+// Command.AddCommand (both promoted from the embedded commandSet).
+type Command struct {
+	Name    string
+	Help    string
+	Aliases []string
+
+	// Flags holds this command's own registered flags. Register flags on it the same way
+	// you would on a root Parser (Flags.String, Flags.Int, ...).
+	Flags *Parser
+
+	handler CommandHandler
+
+	commandSet
+}
+
+// firstCommandToken scans args for the first token that isn't a flag or a flag's value (per the
+// flags registered on known) and returns it as the candidate command name, along with the args
+// before it (flags belonging to the current scope) and after it (the command's own arguments).
+func firstCommandToken(known *Parser, args []string) (name string, before, after []string, ok bool) {
+	skipNext := false
+
+	for i, a := range args {
+		if skipNext {
+			skipNext = false
+			continue
+		}
+
+		if strings.HasPrefix(a, "-") {
+			key := strings.TrimLeft(a, "-")
+			if !strings.Contains(key, "=") {
+				if spec, found := known.resolve(key); found && spec.flagType != TypeBool {
+					skipNext = true
+				}
+			}
+			continue
+		}
+
+		return a, args[:i], args[i+1:], true
+	}
+
+	return "", args, nil, false
+}
+
+// mergeFlags returns a new Parser whose registered flags are the union of child's own flags and
+// its parent's, so a command handler can read both its own and any enclosing flags. Child flags
+// win on name collisions. The parent's already-parsed values (if any) are carried over as
+// presets, so a global flag parsed at the root is still visible arbitrarily deep into nested
+// commands even when their own arguments don't repeat it; see Parser.presets. Config values
+// loaded via ParseIniFile on either Parser are carried over the same way, so a command that
+// loads its own config file still has it honored once Dispatch merges it with the parent. For a
+// flag that has a preset, the preset takes precedence over env and config values.
+func mergeFlags(parent, child *Parser) *Parser {
+	merged := NewParser()
+	merged.Usage = child.Usage
+
+	addFrom := func(src *Parser) {
+		for _, name := range src.order {
+			if _, ok := merged.specs[name]; !ok {
+				merged.order = append(merged.order, name)
+			}
+			merged.specs[name] = src.specs[name]
+		}
+		for short, name := range src.shorts {
+			merged.shorts[short] = name
+		}
+		for name, value := range src.configValues {
+			if merged.configValues == nil {
+				merged.configValues = map[string]Arg{}
+			}
+			merged.configValues[name] = value
+		}
+	}
+
+	if parent != nil {
+		addFrom(parent)
+
+		if len(parent.parsed) > 0 {
+			merged.presets = map[string]Arg{}
+			for name, value := range parent.parsed {
+				merged.presets[name] = value
+			}
+		}
+	}
+	addFrom(child)
+
+	return merged
+}
+
+// Dispatch walks args (os.Args[1:] if nil), looking for the first non-flag token and treating
+// it as a command name. Flags found before the command belong to the root Parser; the remaining
+// arguments are parsed against the matched command's own flags (merged with the root's) and
+// handed to its handler. Dispatch recurses into any nested commands registered via
+// Command.AddCommand, so "mytool remote add --url=... origin" dispatches into "remote", then
+// "add", with --url and "origin" both available to add's handler.
+func (p *Parser) Dispatch(args []string) error {
+	if args == nil {
+		args = os.Args[1:]
+	}
+
+	name, before, after, ok := firstCommandToken(p, args)
+
+	if ok {
+		// The candidate token isn't a registered command/alias; if a default command is set,
+		// it wasn't meant to name a command at all — it's the default command's own positional
+		// arg, so it belongs in after, not in place of name. See SetDefaultCommand.
+		if _, found := p.resolveCommand(name); !found && p.defaultCommand != "" {
+			after = append([]string{name}, after...)
+			name = p.defaultCommand
+		}
+	} else if p.defaultCommand != "" {
+		// after must be an empty slice, not nil: Parser.Parse treats a nil args slice as "use
+		// os.Args", which is only correct when the caller passed nil to Dispatch, not here.
+		name, before, after, ok = p.defaultCommand, args, []string{}, true
+	}
+
+	if !ok {
+		return p.Parse(args)
+	}
+
+	cmd, found := p.resolveCommand(name)
+	if !found {
+		if p.notFound != nil {
+			return p.notFound(name, after)
+		}
+		return fmt.Errorf("gargs: unknown command %q", name)
+	}
+
+	if err := p.Parse(before); err != nil {
+		return err
+	}
+
+	return cmd.dispatch(p, after)
+}
+
+// dispatch resolves nested commands and, once none remain, parses args against this command's
+// flags (merged with parent's) and invokes its handler.
+func (c *Command) dispatch(parent *Parser, args []string) error {
+	merged := mergeFlags(parent, c.Flags)
+
+	if name, before, after, ok := firstCommandToken(merged, args); ok {
+		if nested, found := c.resolveCommand(name); found {
+			if err := merged.Parse(before); err != nil {
+				return err
+			}
+			return nested.dispatch(merged, after)
+		}
+	}
+
+	if err := merged.Parse(args); err != nil {
+		return err
+	}
+
+	if c.handler == nil {
+		return fmt.Errorf("gargs: command %q has no handler", c.Name)
+	}
+
+	return c.handler(merged)
+}
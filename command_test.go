@@ -0,0 +1,187 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDispatchRootOnlyWhenNoCommand(t *testing.T) {
+	root := NewParser()
+	root.Bool("verbose", "v", false, false)
+
+	if err := root.Dispatch([]string{"-v"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !root.GetBool("verbose") {
+		t.Fatalf("expected verbose=true")
+	}
+}
+
+func TestDispatchMatchesCommand(t *testing.T) {
+	root := NewParser()
+
+	var invoked bool
+	var positional string
+	root.AddCommand("serve", "run the server", func(ctx *Parser) error {
+		invoked = true
+		if ctx.NArg() > 0 {
+			positional, _ = ctx.Args()[0].(string)
+		}
+		return nil
+	})
+
+	if err := root.Dispatch([]string{"serve", "site.txt"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !invoked {
+		t.Fatalf("expected serve handler to be invoked")
+	}
+	if positional != "site.txt" {
+		t.Fatalf("positional = %q, want site.txt", positional)
+	}
+}
+
+func TestDispatchAlias(t *testing.T) {
+	root := NewParser()
+
+	var invoked bool
+	root.AddCommand("serve", "run the server", func(ctx *Parser) error {
+		invoked = true
+		return nil
+	}, "s", "run")
+
+	if err := root.Dispatch([]string{"run"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !invoked {
+		t.Fatalf("expected alias dispatch to invoke serve handler")
+	}
+}
+
+func TestDispatchUnknownCommandErrors(t *testing.T) {
+	root := NewParser()
+	root.AddCommand("serve", "run the server", func(ctx *Parser) error { return nil })
+
+	if err := root.Dispatch([]string{"bogus"}); err == nil {
+		t.Fatalf("expected an error for an unknown command")
+	}
+}
+
+func TestDispatchCommandNotFoundHook(t *testing.T) {
+	root := NewParser()
+
+	var gotName string
+	root.OnCommandNotFound(func(name string, args []string) error {
+		gotName = name
+		return nil
+	})
+
+	if err := root.Dispatch([]string{"bogus"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotName != "bogus" {
+		t.Fatalf("notFound hook name = %q, want bogus", gotName)
+	}
+}
+
+func TestDispatchDefaultCommandOnEmptyArgs(t *testing.T) {
+	root := NewParser()
+
+	var invoked bool
+	root.AddCommand("serve", "run the server", func(ctx *Parser) error {
+		invoked = true
+		return nil
+	})
+	root.SetDefaultCommand("serve")
+
+	if err := root.Dispatch([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !invoked {
+		t.Fatalf("expected default command to be invoked on empty args")
+	}
+}
+
+func TestDispatchDefaultCommandWithLeadingPositional(t *testing.T) {
+	root := NewParser()
+	root.String("env", "e", "prod", false)
+
+	var positional string
+	root.AddCommand("serve", "run the server", func(ctx *Parser) error {
+		if ctx.NArg() != 1 {
+			t.Fatalf("expected 1 positional, got %d: %v", ctx.NArg(), ctx.Args())
+		}
+		positional, _ = ctx.Args()[0].(string)
+		return nil
+	})
+	root.SetDefaultCommand("serve")
+
+	if err := root.Dispatch([]string{"file.txt"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if positional != "file.txt" {
+		t.Fatalf("positional = %q, want file.txt", positional)
+	}
+}
+
+func TestDispatchNestedCommandsAndPresets(t *testing.T) {
+	root := NewParser()
+	root.String("env", "e", "prod", false)
+
+	remote := root.AddCommand("remote", "manage remotes", nil)
+
+	var gotURL, gotEnv, gotName string
+	remote.AddCommand("add", "add a remote", func(ctx *Parser) error {
+		gotURL = ctx.GetString("url")
+		gotEnv = ctx.GetString("env")
+		if ctx.NArg() > 0 {
+			gotName, _ = ctx.Args()[0].(string)
+		}
+		return nil
+	})
+	remote.Flags.String("url", "u", "", false)
+
+	if err := root.Dispatch([]string{"--env", "staging", "remote", "add", "--url=https://example.com", "origin"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotURL != "https://example.com" {
+		t.Fatalf("gotURL = %q", gotURL)
+	}
+	if gotEnv != "staging" {
+		t.Fatalf("gotEnv = %q, want staging (preset from root)", gotEnv)
+	}
+	if gotName != "origin" {
+		t.Fatalf("gotName = %q, want origin", gotName)
+	}
+}
+
+func TestDispatchHonorsCommandScopedIniValues(t *testing.T) {
+	root := NewParser()
+
+	var gotPort int
+	serve := root.AddCommand("serve", "run the server", func(ctx *Parser) error {
+		gotPort = ctx.GetInt("port")
+		return nil
+	})
+	serve.Flags.Int("port", "p", 8080, false)
+
+	if err := serve.Flags.readIni(strings.NewReader("port=9999\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := root.Dispatch([]string{"serve"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPort != 9999 {
+		t.Fatalf("gotPort = %d, want 9999 (from command-scoped ini)", gotPort)
+	}
+}
+
+func TestCommandMissingHandlerErrors(t *testing.T) {
+	root := NewParser()
+	root.AddCommand("remote", "manage remotes", nil)
+
+	if err := root.Dispatch([]string{"remote"}); err == nil {
+		t.Fatalf("expected an error dispatching to a command with no handler")
+	}
+}
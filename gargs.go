@@ -5,6 +5,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/JasonPollman/Go-Practice/sliceutil"
 )
 
 // Arg An argument type.
@@ -57,6 +59,10 @@ func coerceArgument(value string) Arg {
 // be put into the "_" []Arg key.
 //
 // -- Is typically an indicator to stop parsing arguments as they are intended for another program.
+//
+// Parse is a thin wrapper around the untyped parsing engine also used internally by Parser
+// (see NewParser). It exists for callers that don't need registered flags, validation or
+// defaults and are happy with the free-form map API.
 func Parse(args []string) (parsed map[string]Arg, err error) {
 	defer func() {
 		if e := recover(); e != nil {
@@ -64,6 +70,22 @@ func Parse(args []string) (parsed map[string]Arg, err error) {
 		}
 	}()
 
+	return parseUntyped(args, nil)
+}
+
+// shortOptionSchema tells parseUntyped which single-character options are known and which of
+// those expect a value, so it can split "-p8080"/"-p=80"/"-lap 80" correctly instead of always
+// bundling every character into a boolean. A nil schema (used by the free-form Parse) preserves
+// the original fully-bundled-boolean behavior.
+type shortOptionSchema struct {
+	isRegistered func(name string) bool
+	takesValue   func(name string) bool
+}
+
+// parseUntyped is the engine behind Parse. It is factored out so that Parser can reuse the same
+// tokenization and coercion rules instead of duplicating them. schema is nil for the free-form
+// Parse and non-nil when called from Parser.Parse, which knows its registered short options.
+func parseUntyped(args []string, schema *shortOptionSchema) (parsed map[string]Arg, err error) {
 	// Use arguments without go executable filepath if nil is passed
 	if args == nil {
 		args = os.Args[1:]
@@ -81,8 +103,8 @@ func Parse(args []string) (parsed map[string]Arg, err error) {
 	// Trim, replace all "=" with tokens, and flatten out each argument set into a single array
 	// This will convert, for example: [a, b, c, d=3, --f=4, -foo=bar] to [a, b, c, d=3, f, 4, foo, bar]
 	var sanitized []string
-	for _, v := range args {
-		value := strings.Trim(v, " ")
+	for idx := 0; idx < len(args); idx++ {
+		value := strings.Trim(args[idx], " ")
 
 		// Stop parsing arguments after the empty flag (--)
 		if value == "--" {
@@ -97,13 +119,46 @@ func Parse(args []string) (parsed map[string]Arg, err error) {
 			sanitized = append(sanitized, strings.Split(strings.Replace(value, "=", " ", 1), " ")...)
 		} else if strings.HasPrefix(value, "-") {
 			// Options (-)
-			options := strings.Split(strings.Replace(value, "-", "", 1), "")
-			for _, v := range options {
-				parsed[v] = true
+			body := strings.TrimPrefix(value, "-")
+
+			if schema == nil {
+				for _, r := range strings.Split(body, "") {
+					parsed[r] = true
+				}
+				continue
+			}
+
+			flags, val, hasValue, needsNext, splitErr := splitShortOptions(body, schema.isRegistered, schema.takesValue)
+			if splitErr != nil {
+				return nil, splitErr
+			}
+
+			// Every short in the bundle before a value-taking one (if any) is a plain boolean.
+			boolCount := len(flags)
+			if hasValue || needsNext {
+				boolCount--
+			}
+
+			for _, name := range flags[:boolCount] {
+				parsed[name] = true
+			}
+
+			switch {
+			case hasValue:
+				parsed[flags[len(flags)-1]] = coerceArgument(val)
+			case needsNext:
+				name := flags[len(flags)-1]
+
+				if idx+1 < len(args) && !strings.HasPrefix(args[idx+1], "-") {
+					idx++
+					parsed[name] = coerceArgument(strings.Trim(args[idx], " "))
+				} else {
+					parsed[name] = true
+				}
 			}
 		} else {
 			// Regular ole command line arg
-			sanitized = append(sanitized, v)
+			sanitized = append(sanitized, args[idx])
 		}
 	}
 
@@ -153,7 +208,7 @@ func Parse(args []string) (parsed map[string]Arg, err error) {
 		}
 	}
 
-	parsed["_"] = append(plain, escapedArgs...)
+	parsed["_"] = sliceutil.Flatten([][]Arg{plain, escapedArgs})
 	return parsed, err
 }
 
@@ -166,4 +221,8 @@ func ParseArgs() (parsed map[string]Arg, err error) {
 func main() {
 	v, _ := ParseArgs()
 	fmt.Printf("%v\n", v)
+
+	if first, ok := sliceutil.First(v["_"].([]Arg)); ok {
+		fmt.Printf("first positional arg: %v\n", first)
+	}
 }
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrHelp is returned by Parser.Parse (and Dispatch) when -h/--help was present in the parsed
+// arguments. It signals "usage was printed, stop" rather than a validation failure, so callers
+// should check for it with errors.Is and exit 0 instead of reporting it as an error.
+var ErrHelp = errors.New("gargs: help requested")
+
+// typeName renders a FlagType the way it should appear in usage output, e.g. "int" (which
+// flagUsageLeft wraps as "<int>").
+func typeName(t FlagType) string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeInt:
+		return "int"
+	case TypeFloat:
+		return "float"
+	case TypeBool:
+		return "bool"
+	case TypeStringSlice:
+		return "strings"
+	case TypeVar:
+		return "value"
+	default:
+		return "value"
+	}
+}
+
+// PrintUsage writes aligned usage text for every registered flag to w: a left column of
+// "-o, --option <type>" and a right column of description, required/default/env-var notes,
+// grouped the way the flags were registered with Group (ungrouped flags print first). Registered
+// commands, if any, are listed beneath the flags.
+func (p *Parser) PrintUsage(w io.Writer) {
+	if p.Usage != "" {
+		fmt.Fprintln(w, "Usage:", p.Usage)
+		fmt.Fprintln(w)
+	}
+
+	groups := map[string][]*flagSpec{}
+	var groupOrder []string
+
+	for _, name := range p.order {
+		spec := p.specs[name]
+
+		if _, ok := groups[spec.group]; !ok {
+			groupOrder = append(groupOrder, spec.group)
+		}
+
+		groups[spec.group] = append(groups[spec.group], spec)
+	}
+
+	var rows [][2]string
+	for _, group := range groupOrder {
+		if group != "" {
+			rows = append(rows, [2]string{"", ""})
+			rows = append(rows, [2]string{group + ":", ""})
+		}
+
+		for _, spec := range groups[group] {
+			rows = append(rows, [2]string{flagUsageLeft(spec), flagUsageRight(spec)})
+		}
+	}
+
+	width := 0
+	for _, row := range rows {
+		if len(row[0]) > width {
+			width = len(row[0])
+		}
+	}
+
+	for _, row := range rows {
+		// Group headers and blank separators don't start with "-"; flag rows always do, and
+		// stay aligned even when they have no notes.
+		if !strings.HasPrefix(row[0], "-") {
+			fmt.Fprintln(w, row[0])
+			continue
+		}
+		fmt.Fprintln(w, strings.TrimRight(fmt.Sprintf("  %-*s   %s", width, row[0], row[1]), " "))
+	}
+
+	if len(p.order) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	if len(p.commandSet.order) > 0 {
+		fmt.Fprintln(w, "Commands:")
+		for _, name := range p.commandSet.order {
+			cmd := p.commands[name]
+			fmt.Fprintf(w, "  %-*s   %s\n", width, commandUsageLeft(cmd), cmd.Help)
+		}
+	}
+}
+
+// flagUsageLeft renders the "-o, --option <type>" left column for a flag.
+func flagUsageLeft(spec *flagSpec) string {
+	var left string
+	if spec.short != "" {
+		left = fmt.Sprintf("-%s, --%s", spec.short, spec.name)
+	} else {
+		left = fmt.Sprintf("--%s", spec.name)
+	}
+
+	return fmt.Sprintf("%s <%s>", left, typeName(spec.flagType))
+}
+
+// flagUsageRight renders the description plus required/default/env-var notes for a flag.
+func flagUsageRight(spec *flagSpec) string {
+	var notes []string
+
+	if spec.description != "" {
+		notes = append(notes, spec.description)
+	}
+
+	if spec.required {
+		notes = append(notes, "[required]")
+	} else if spec.def != nil && spec.def != "" && spec.def != false {
+		notes = append(notes, fmt.Sprintf("[default: %v]", spec.def))
+	}
+
+	if spec.envVar != "" {
+		notes = append(notes, fmt.Sprintf("[env: %s]", spec.envVar))
+	}
+
+	return strings.Join(notes, " ")
+}
+
+// commandUsageLeft renders the "name, alias1, alias2" left column for a command.
+func commandUsageLeft(cmd *Command) string {
+	if len(cmd.Aliases) == 0 {
+		return cmd.Name
+	}
+	return cmd.Name + ", " + strings.Join(cmd.Aliases, ", ")
+}
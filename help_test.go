@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintUsageListsFlags(t *testing.T) {
+	p := NewParser()
+	p.Usage = "mytool [options] <file>"
+	p.String("name", "n", "anon", false, Describe("the name to use"))
+	p.Int("port", "p", 8080, false, Describe("the port to listen on"))
+	p.String("token", "", "", true, Group("auth"), Env("MYTOOL_TOKEN"))
+
+	var buf bytes.Buffer
+	p.PrintUsage(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"Usage: mytool [options] <file>",
+		"-n, --name <string>",
+		"the name to use",
+		"[default: anon]",
+		"-p, --port <int>",
+		"auth:",
+		"--token <string>",
+		"[required]",
+		"[env: MYTOOL_TOKEN]",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("usage output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintUsageListsCommands(t *testing.T) {
+	p := NewParser()
+	p.AddCommand("serve", "run the server", func(ctx *Parser) error { return nil }, "s")
+
+	var buf bytes.Buffer
+	p.PrintUsage(&buf)
+	out := buf.String()
+
+	if !strings.Contains(out, "Commands:") {
+		t.Fatalf("usage output missing Commands section:\n%s", out)
+	}
+	if !strings.Contains(out, "serve, s") {
+		t.Fatalf("usage output missing command+alias listing:\n%s", out)
+	}
+}
+
+func TestTypeNameCoversAllFlagTypes(t *testing.T) {
+	cases := map[FlagType]string{
+		TypeString:      "string",
+		TypeInt:         "int",
+		TypeFloat:       "float",
+		TypeBool:        "bool",
+		TypeStringSlice: "strings",
+		TypeVar:         "value",
+	}
+
+	for ft, want := range cases {
+		if got := typeName(ft); got != want {
+			t.Fatalf("typeName(%v) = %q, want %q", ft, got, want)
+		}
+	}
+}
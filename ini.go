@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ParseIniFile loads key=value pairs from an INI-style config file at path and records them as
+// config-file values for the registered flags (see Parse for precedence). A "[section]" header
+// names an option group (see Group); keys under it apply to flags registered in that group,
+// keys before any header apply to any registered flag, whatever its group. CLI flags and
+// EnvVar values still take precedence over whatever is loaded here.
+func (p *Parser) ParseIniFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("gargs: ParseIniFile: %w", err)
+	}
+	defer f.Close()
+
+	return p.readIni(f)
+}
+
+// readIni does the actual scanning so ParseIniFile can be exercised in tests without a file on
+// disk.
+func (p *Parser) readIni(r io.Reader) error {
+	if p.configValues == nil {
+		p.configValues = map[string]Arg{}
+	}
+
+	section := ""
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		spec, known := p.specs[key]
+		if known && section != "" && spec.group != section {
+			continue
+		}
+
+		// A key repeated across multiple lines (as WriteIni emits for a StringSlice flag)
+		// accumulates into a slice, the same way a flag repeated on the command line does in
+		// parseUntyped. A repeated key for anything else is a config error, not a panic.
+		if existing, ok := p.configValues[key]; ok {
+			existingSlice, isSlice := existing.([]Arg)
+			if !isSlice && !(known && spec.flagType == TypeStringSlice) {
+				return fmt.Errorf("gargs: ini: key %q is repeated but is not a StringSlice flag", key)
+			}
+			if !isSlice {
+				existingSlice = []Arg{existing}
+			}
+
+			p.configValues[key] = append(existingSlice, coerceArgument(value))
+		} else if known && spec.flagType == TypeStringSlice {
+			p.configValues[key] = []Arg{coerceArgument(value)}
+		} else {
+			p.configValues[key] = coerceArgument(value)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// WriteIni writes the current flag values (as produced by the most recent Parse) back out in
+// the format ParseIniFile reads: grouped under "[group]" headers in registration order, with
+// each flag's registered description emitted as a comment above it.
+func (p *Parser) WriteIni(w io.Writer) error {
+	groups := map[string][]string{}
+	var groupOrder []string
+
+	for _, name := range p.order {
+		spec := p.specs[name]
+
+		if _, ok := groups[spec.group]; !ok {
+			groupOrder = append(groupOrder, spec.group)
+		}
+
+		groups[spec.group] = append(groups[spec.group], name)
+	}
+
+	bw := bufio.NewWriter(w)
+
+	for _, group := range groupOrder {
+		if group != "" {
+			fmt.Fprintf(bw, "[%s]\n", group)
+		}
+
+		for _, name := range groups[group] {
+			spec := p.specs[name]
+
+			if spec.description != "" {
+				fmt.Fprintf(bw, "; %s\n", spec.description)
+			}
+
+			if slice, ok := p.parsed[name].([]string); ok {
+				// One name=value line per element, so readIni's repeated-key accumulation
+				// reconstructs the slice instead of the bracketed %v rendering it can't split.
+				for _, elem := range slice {
+					fmt.Fprintf(bw, "%s=%v\n", name, elem)
+				}
+				continue
+			}
+
+			fmt.Fprintf(bw, "%s=%v\n", name, p.parsed[name])
+		}
+
+		fmt.Fprintln(bw)
+	}
+
+	return bw.Flush()
+}
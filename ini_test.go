@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseIniFileAppliesSectionedValues(t *testing.T) {
+	p := NewParser()
+	p.String("name", "n", "anon", false)
+	p.String("token", "", "", false, Group("auth"))
+
+	ini := strings.NewReader("name=alice\n[auth]\ntoken=secret\n")
+	if err := p.readIni(ini); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := p.Parse([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := p.GetString("name"); got != "alice" {
+		t.Fatalf("GetString(name) = %q, want alice", got)
+	}
+	if got := p.GetString("token"); got != "secret" {
+		t.Fatalf("GetString(token) = %q, want secret", got)
+	}
+}
+
+func TestReadIniDuplicateNonSliceKeyErrors(t *testing.T) {
+	p := NewParser()
+	p.String("name", "n", "anon", false)
+
+	err := p.readIni(strings.NewReader("name=foo\nname=bar\n"))
+	if err == nil {
+		t.Fatalf("expected an error for a duplicate non-slice key, got none")
+	}
+}
+
+func TestReadIniDuplicateUnregisteredKeyErrors(t *testing.T) {
+	p := NewParser()
+
+	err := p.readIni(strings.NewReader("mystery=foo\nmystery=bar\n"))
+	if err == nil {
+		t.Fatalf("expected an error for a duplicate unregistered key, got none")
+	}
+}
+
+func TestIniSectionSkipsFlagsFromOtherGroups(t *testing.T) {
+	p := NewParser()
+	p.String("name", "n", "default-name", false)
+
+	ini := strings.NewReader("[auth]\nname=should-not-apply\n")
+	if err := p.readIni(ini); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.Parse([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := p.GetString("name"); got != "default-name" {
+		t.Fatalf("GetString(name) = %q, want default-name (section mismatch should be ignored)", got)
+	}
+}
+
+func TestPrecedenceDefaultsThenConfigThenEnvThenCLI(t *testing.T) {
+	p := NewParser()
+	p.Int("port", "p", 1111, false, Env("GARGS_TEST_PRECEDENCE_PORT"))
+
+	// Defaults only.
+	if err := p.Parse([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.GetInt("port"); got != 1111 {
+		t.Fatalf("with nothing set, GetInt(port) = %d, want 1111 (default)", got)
+	}
+
+	// Config file beats default.
+	if err := p.readIni(strings.NewReader("port=2222\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.Parse([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.GetInt("port"); got != 2222 {
+		t.Fatalf("with config set, GetInt(port) = %d, want 2222 (config file)", got)
+	}
+
+	// Env beats config file.
+	os.Setenv("GARGS_TEST_PRECEDENCE_PORT", "3333")
+	defer os.Unsetenv("GARGS_TEST_PRECEDENCE_PORT")
+	if err := p.Parse([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.GetInt("port"); got != 3333 {
+		t.Fatalf("with env set, GetInt(port) = %d, want 3333 (env)", got)
+	}
+
+	// CLI beats env.
+	if err := p.Parse([]string{"--port", "4444"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.GetInt("port"); got != 4444 {
+		t.Fatalf("with CLI set, GetInt(port) = %d, want 4444 (CLI)", got)
+	}
+}
+
+func TestWriteIniRoundTripsStringSlice(t *testing.T) {
+	p := NewParser()
+	p.StringSlice("tag", "t", nil, false, Describe("tags to apply"))
+
+	if err := p.Parse([]string{"--tag", "a", "--tag", "b"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteIni(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p2 := NewParser()
+	p2.StringSlice("tag", "t", nil, false)
+
+	if err := p2.readIni(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p2.Parse([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := p2.GetStringSlice("tag")
+	if want := []string{"a", "b"}; !equalStrings(got, want) {
+		t.Fatalf("round-tripped GetStringSlice(tag) = %v, want %v", got, want)
+	}
+}
+
+func TestWriteIniIncludesDescriptionComments(t *testing.T) {
+	p := NewParser()
+	p.String("name", "n", "anon", false, Describe("the name to use"))
+
+	if err := p.Parse([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteIni(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out := buf.String(); !strings.Contains(out, "; the name to use") {
+		t.Fatalf("WriteIni output missing description comment:\n%s", out)
+	}
+}
@@ -0,0 +1,407 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// FlagType identifies the target type a registered flag coerces its value into.
+type FlagType int
+
+// Supported flag types for the schema-based Parser API.
+const (
+	TypeString FlagType = iota
+	TypeInt
+	TypeFloat
+	TypeBool
+	TypeStringSlice
+	TypeVar
+)
+
+// TypedValue is implemented by user types that want to be the target of a flag registered via
+// Parser.Var. It mirrors the standard library's flag.Value interface so custom types can plug
+// into the schema-based API without gargs needing to know about them.
+type TypedValue interface {
+	Set(string) error
+	String() string
+}
+
+// flagSpec holds everything the Parser knows about a single registered flag.
+type flagSpec struct {
+	name     string
+	short    string
+	flagType FlagType
+	def      Arg
+	required bool
+	value    TypedValue
+
+	description string
+	group       string
+	envVar      string
+}
+
+// FlagOption configures optional metadata (description, option group, environment variable
+// fallback) on a flag at registration time, using the functional options pattern.
+type FlagOption func(*flagSpec)
+
+// Describe sets the help text shown for a flag in usage output.
+func Describe(description string) FlagOption {
+	return func(s *flagSpec) { s.description = description }
+}
+
+// Group assigns a flag to a named option group for usage output, mirroring go-flags' groups.
+// Flags without a group are printed first, ungrouped.
+func Group(group string) FlagOption {
+	return func(s *flagSpec) { s.group = group }
+}
+
+// Env sets an environment variable to fall back to when the flag isn't passed on the command
+// line. Precedence is CLI flag, then environment variable, then a config-file value (see
+// ParseIniFile), then the registered default.
+func Env(envVar string) FlagOption {
+	return func(s *flagSpec) { s.envVar = envVar }
+}
+
+// Parser is a schema-based CLI argument parser. Unlike the free-form Parse function, a Parser
+// knows its expected flags ahead of time, so it can validate types, apply defaults, enforce
+// required flags and hand back typed accessors instead of forcing callers to type-assert Arg
+// at every call site.
+//
+// A zero-value Parser is not usable; construct one with NewParser.
+type Parser struct {
+	// Usage is printed above the flag listing by PrintUsage, e.g. "mytool [options] <file>".
+	Usage string
+
+	// ShortOptionsTakeValues, when true, makes every registered short option on this Parser
+	// consume a value (the remainder of its token, or the next argument) instead of only those
+	// registered with a non-bool type. See splitShortOptions.
+	ShortOptionsTakeValues bool
+
+	specs  map[string]*flagSpec
+	shorts map[string]string // short name -> long name
+	order  []string          // registration order, for stable usage output
+
+	parsed map[string]Arg
+	args   []Arg
+
+	// configValues holds values loaded by ParseIniFile, keyed by flag name. They sit between
+	// the registered default and an EnvVar/CLI value in precedence; see Parse.
+	configValues map[string]Arg
+
+	// presets holds already-parsed values inherited from an enclosing command's Parser, set up
+	// by mergeFlags in command.go. A flag not seen at this scope falls back to its preset before
+	// any environment, config-file or registered default value (the preset already went through
+	// that precedence in the enclosing scope), so a global flag parsed at the root is still
+	// visible several commands deep even when it isn't repeated in the command's own arguments.
+	presets map[string]Arg
+
+	// commandSet gives every Parser a subcommand registry (AddCommand, SetDefaultCommand,
+	// OnCommandNotFound) for free; see Dispatch in command.go.
+	commandSet
+}
+
+// NewParser creates a Parser with a pre-registered -h/--help flag; see PrintUsage.
+func NewParser() *Parser {
+	p := &Parser{
+		specs:  map[string]*flagSpec{},
+		shorts: map[string]string{},
+	}
+
+	p.Bool("help", "h", false, false, Describe("Show this help message"))
+
+	return p
+}
+
+// register records a flagSpec under both its long and (optional) short name.
+func (p *Parser) register(name, short string, flagType FlagType, def Arg, required bool, opts ...FlagOption) *flagSpec {
+	spec := &flagSpec{name: name, short: short, flagType: flagType, def: def, required: required}
+
+	for _, opt := range opts {
+		opt(spec)
+	}
+
+	p.specs[name] = spec
+	p.order = append(p.order, name)
+
+	if short != "" {
+		p.shorts[short] = name
+	}
+
+	return spec
+}
+
+// String registers a string flag. def is used when the flag is absent and required is false.
+func (p *Parser) String(name, short string, def string, required bool, opts ...FlagOption) {
+	p.register(name, short, TypeString, def, required, opts...)
+}
+
+// Int registers an integer flag. def is used when the flag is absent and required is false.
+func (p *Parser) Int(name, short string, def int, required bool, opts ...FlagOption) {
+	p.register(name, short, TypeInt, def, required, opts...)
+}
+
+// Float registers a float64 flag. def is used when the flag is absent and required is false.
+func (p *Parser) Float(name, short string, def float64, required bool, opts ...FlagOption) {
+	p.register(name, short, TypeFloat, def, required, opts...)
+}
+
+// Bool registers a boolean flag. def is used when the flag is absent and required is false.
+func (p *Parser) Bool(name, short string, def bool, required bool, opts ...FlagOption) {
+	p.register(name, short, TypeBool, def, required, opts...)
+}
+
+// StringSlice registers a flag that accumulates every occurrence of the flag into a []string,
+// in the order they were parsed.
+func (p *Parser) StringSlice(name, short string, def []string, required bool, opts ...FlagOption) {
+	p.register(name, short, TypeStringSlice, def, required, opts...)
+}
+
+// Var registers a flag whose value is owned by a user-provided TypedValue. Set is called once
+// per occurrence of the flag. The string it receives is the value after gargs' own coercion,
+// rendered with %v, not the exact command-line text: "0x10" arrives as "16", "1.50" as "1.5".
+func (p *Parser) Var(value TypedValue, name, short string, required bool, opts ...FlagOption) {
+	spec := p.register(name, short, TypeVar, nil, required, opts...)
+	spec.value = value
+}
+
+// resolve maps a raw key parsed by parseUntyped (long flag name or short option) to the
+// registered flag name it belongs to, if any.
+func (p *Parser) resolve(key string) (*flagSpec, bool) {
+	if spec, ok := p.specs[key]; ok {
+		return spec, true
+	}
+
+	if name, ok := p.shorts[key]; ok {
+		return p.specs[name], true
+	}
+
+	return nil, false
+}
+
+// Parse parses args (os.Args[1:] if nil) against the registered schema. Unlike Parse/ParseArgs,
+// unknown types, missing required flags and bad coercions are reported as a real error rather
+// than a recovered panic.
+//
+// If -h/--help is present, Parse prints usage to os.Stdout and returns ErrHelp instead of
+// validating the rest of the flags.
+func (p *Parser) Parse(args []string) error {
+	schema := &shortOptionSchema{
+		isRegistered: func(name string) bool {
+			_, ok := p.shorts[name]
+			return ok
+		},
+		takesValue: func(name string) bool {
+			long, ok := p.shorts[name]
+			if !ok {
+				return false
+			}
+
+			return p.ShortOptionsTakeValues || p.specs[long].flagType != TypeBool
+		},
+	}
+
+	raw, err := parseUntyped(args, schema)
+	if err != nil {
+		return err
+	}
+
+	if wantsHelp(raw) {
+		p.PrintUsage(os.Stdout)
+		return ErrHelp
+	}
+
+	parsed := map[string]Arg{}
+	seen := map[string]bool{}
+
+	for key, value := range raw {
+		if key == "_" {
+			continue
+		}
+
+		spec, ok := p.resolve(key)
+		if !ok {
+			continue
+		}
+
+		seen[spec.name] = true
+
+		coerced, err := coerceFlag(spec, value)
+		if err != nil {
+			return fmt.Errorf("gargs: flag --%s: %w", spec.name, err)
+		}
+
+		parsed[spec.name] = coerced
+	}
+
+	for name, spec := range p.specs {
+		if seen[name] {
+			continue
+		}
+
+		// Precedence below a CLI value: a value already parsed by an enclosing command (see
+		// presets), then environment, then config file, then the registered default. See
+		// ParseIniFile for where configValues is populated.
+		if v, ok := p.presets[name]; ok {
+			parsed[name] = v
+			continue
+		}
+
+		if spec.envVar != "" {
+			if env, ok := os.LookupEnv(spec.envVar); ok {
+				coerced, err := coerceFlag(spec, coerceArgument(env))
+				if err != nil {
+					return fmt.Errorf("gargs: flag --%s: env %s: %w", name, spec.envVar, err)
+				}
+
+				parsed[name] = coerced
+				continue
+			}
+		}
+
+		if v, ok := p.configValues[name]; ok {
+			coerced, err := coerceFlag(spec, v)
+			if err != nil {
+				return fmt.Errorf("gargs: flag --%s: config file: %w", name, err)
+			}
+
+			parsed[name] = coerced
+			continue
+		}
+
+		if spec.required {
+			return fmt.Errorf("gargs: missing required flag --%s", name)
+		}
+
+		parsed[name] = spec.def
+	}
+
+	p.parsed = parsed
+	p.args, _ = raw["_"].([]Arg)
+
+	return nil
+}
+
+// wantsHelp reports whether the raw, untyped parse result asked for help via -h or --help.
+func wantsHelp(raw map[string]Arg) bool {
+	for _, key := range []string{"help", "h"} {
+		if b, ok := raw[key].(bool); ok && b {
+			return true
+		}
+	}
+	return false
+}
+
+// coerceFlag converts the raw, untyped value produced by parseUntyped into the type the flag
+// was registered with.
+func coerceFlag(spec *flagSpec, value Arg) (Arg, error) {
+	switch spec.flagType {
+	case TypeString:
+		return fmt.Sprintf("%v", value), nil
+	case TypeInt:
+		switch v := value.(type) {
+		case float64:
+			return int(v), nil
+		case uint64:
+			return int(v), nil
+		default:
+			return nil, fmt.Errorf("expected an int, got %v", v)
+		}
+	case TypeFloat:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case uint64:
+			return float64(v), nil
+		default:
+			return nil, fmt.Errorf("expected a float, got %v", v)
+		}
+	case TypeBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		default:
+			return nil, fmt.Errorf("expected a bool, got %v", v)
+		}
+	case TypeStringSlice:
+		switch v := value.(type) {
+		case []Arg:
+			// A flag repeated three or more times arrives nested ([]Arg{[]Arg{a, b}, c}), so
+			// nested slices are flattened in order.
+			out := make([]string, 0, len(v))
+			for _, item := range v {
+				items, _ := coerceFlag(spec, item)
+				out = append(out, items.([]string)...)
+			}
+			return out, nil
+		default:
+			return []string{fmt.Sprintf("%v", v)}, nil
+		}
+	case TypeVar:
+		// A repeated flag arrives as (possibly nested) []Arg; Set each occurrence in turn.
+		if items, ok := value.([]Arg); ok {
+			for _, item := range items {
+				if _, err := coerceFlag(spec, item); err != nil {
+					return nil, err
+				}
+			}
+			return spec.value, nil
+		}
+		if err := spec.value.Set(fmt.Sprintf("%v", value)); err != nil {
+			return nil, err
+		}
+		return spec.value, nil
+	default:
+		return nil, fmt.Errorf("unknown flag type %v", spec.flagType)
+	}
+}
+
+// GetString returns the string value of a registered string flag.
+func (p *Parser) GetString(name string) string {
+	if v, ok := p.parsed[name].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// GetInt returns the int value of a registered int flag.
+func (p *Parser) GetInt(name string) int {
+	if v, ok := p.parsed[name].(int); ok {
+		return v
+	}
+	return 0
+}
+
+// GetFloat returns the float64 value of a registered float flag.
+func (p *Parser) GetFloat(name string) float64 {
+	if v, ok := p.parsed[name].(float64); ok {
+		return v
+	}
+	return 0
+}
+
+// GetBool returns the bool value of a registered bool flag.
+func (p *Parser) GetBool(name string) bool {
+	if v, ok := p.parsed[name].(bool); ok {
+		return v
+	}
+	return false
+}
+
+// GetStringSlice returns the []string value of a registered string-slice flag.
+func (p *Parser) GetStringSlice(name string) []string {
+	if v, ok := p.parsed[name].([]string); ok {
+		return v
+	}
+	return nil
+}
+
+// Args returns the positional arguments left over after flag parsing, including everything
+// after a literal "--".
+func (p *Parser) Args() []Arg {
+	return p.args
+}
+
+// NArg returns the number of positional arguments returned by Args.
+func (p *Parser) NArg() int {
+	return len(p.args)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestParserTypedDefaults(t *testing.T) {
+	p := NewParser()
+	p.String("name", "n", "anon", false)
+	p.Int("port", "p", 8080, false)
+	p.Bool("verbose", "v", false, false)
+
+	if err := p.Parse([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := p.GetString("name"); got != "anon" {
+		t.Fatalf("GetString(name) = %q, want anon", got)
+	}
+	if got := p.GetInt("port"); got != 8080 {
+		t.Fatalf("GetInt(port) = %d, want 8080", got)
+	}
+	if got := p.GetBool("verbose"); got != false {
+		t.Fatalf("GetBool(verbose) = %v, want false", got)
+	}
+}
+
+func TestParserIntAcceptsHex(t *testing.T) {
+	p := NewParser()
+	p.Int("port", "p", 0, false)
+
+	if err := p.Parse([]string{"--port", "0x1A"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.GetInt("port"); got != 26 {
+		t.Fatalf("GetInt(port) = %d, want 26", got)
+	}
+}
+
+func TestParserFloatAcceptsHex(t *testing.T) {
+	p := NewParser()
+	p.Float("size", "s", 0, false)
+
+	if err := p.Parse([]string{"--size", "0x10"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.GetFloat("size"); got != 16 {
+		t.Fatalf("GetFloat(size) = %v, want 16", got)
+	}
+}
+
+func TestParserIntRejectsNonNumeric(t *testing.T) {
+	p := NewParser()
+	p.Int("port", "p", 0, false)
+
+	if err := p.Parse([]string{"--port", "notanumber"}); err == nil {
+		t.Fatalf("expected an error for a non-numeric int flag")
+	}
+}
+
+func TestParserRequiredFlagMissing(t *testing.T) {
+	p := NewParser()
+	p.String("name", "n", "", true)
+
+	if err := p.Parse([]string{}); err == nil {
+		t.Fatalf("expected an error for a missing required flag")
+	}
+}
+
+func TestParserStringSliceAccumulates(t *testing.T) {
+	p := NewParser()
+	p.StringSlice("tag", "t", nil, false)
+
+	if err := p.Parse([]string{"--tag", "a", "--tag", "b"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := p.GetStringSlice("tag")
+	if want := []string{"a", "b"}; !equalStrings(got, want) {
+		t.Fatalf("GetStringSlice(tag) = %v, want %v", got, want)
+	}
+}
+
+func TestParserEnvFallback(t *testing.T) {
+	os.Setenv("GARGS_TEST_PORT", "9090")
+	defer os.Unsetenv("GARGS_TEST_PORT")
+
+	p := NewParser()
+	p.Int("port", "p", 0, false, Env("GARGS_TEST_PORT"))
+
+	if err := p.Parse([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.GetInt("port"); got != 9090 {
+		t.Fatalf("GetInt(port) = %d, want 9090 from env", got)
+	}
+}
+
+func TestParserCLIOverridesEnv(t *testing.T) {
+	os.Setenv("GARGS_TEST_PORT", "9090")
+	defer os.Unsetenv("GARGS_TEST_PORT")
+
+	p := NewParser()
+	p.Int("port", "p", 0, false, Env("GARGS_TEST_PORT"))
+
+	if err := p.Parse([]string{"--port", "1234"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.GetInt("port"); got != 1234 {
+		t.Fatalf("GetInt(port) = %d, want 1234 from CLI", got)
+	}
+}
+
+func TestParserHelpShortCircuits(t *testing.T) {
+	p := NewParser()
+	p.String("name", "n", "", false)
+
+	err := p.Parse([]string{"--help"})
+	if !errors.Is(err, ErrHelp) {
+		t.Fatalf("Parse(--help) error = %v, want ErrHelp", err)
+	}
+}
+
+func TestParserArgsAndNArg(t *testing.T) {
+	p := NewParser()
+	p.Bool("verbose", "v", false, false)
+
+	if err := p.Parse([]string{"-v", "one", "two", "--", "three"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if p.NArg() != 3 {
+		t.Fatalf("NArg() = %d, want 3: %v", p.NArg(), p.Args())
+	}
+}
+
+type upperValue struct{ v string }
+
+func (u *upperValue) Set(s string) error { u.v = s; return nil }
+func (u *upperValue) String() string     { return u.v }
+
+func TestParserVar(t *testing.T) {
+	p := NewParser()
+	var v upperValue
+	p.Var(&v, "mode", "m", false)
+
+	if err := p.Parse([]string{"--mode", "fast"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.v != "fast" {
+		t.Fatalf("TypedValue.Set not called with parsed value, got %q", v.v)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitShortOptions decodes a POSIX-style short-option token (without its leading "-"), such as
+// "la", "p8080", "p=80" or "p", against a schema that knows which short names are registered and
+// which of those expect a value rather than acting as a boolean switch.
+//
+// Bundled booleans (e.g. "la" -> l=true, a=true) are the fallback whenever no short in the
+// bundle expects a value. The first short in the bundle that does expect a value consumes
+// whatever follows it in the token (after an optional "=") as its value; every short before it
+// in the bundle is a plain boolean. If nothing follows it in the token, needsNext is true and
+// the caller should consume the next CLI argument instead.
+//
+// It is an error for a value-taking short to be followed, without an explicit "=", by more
+// characters that are themselves registered short options -- that almost certainly means the
+// caller meant to bundle further flags, not pass them as a literal value.
+func splitShortOptions(body string, isRegistered, takesValue func(name string) bool) (flags []string, value string, hasValue, needsNext bool, err error) {
+	for i := 0; i < len(body); i++ {
+		name := string(body[i])
+		flags = append(flags, name)
+
+		if !takesValue(name) {
+			continue
+		}
+
+		rest := body[i+1:]
+
+		if strings.HasPrefix(rest, "=") {
+			return flags, rest[1:], true, false, nil
+		}
+
+		if rest == "" {
+			return flags, "", false, true, nil
+		}
+
+		if allRegistered(rest, isRegistered) {
+			return nil, "", false, false, fmt.Errorf(
+				"gargs: ambiguous short option bundle -%s: -%s takes a value but is followed by other short options (use -%s=value or -%s value)",
+				body, name, name, name,
+			)
+		}
+
+		return flags, rest, true, false, nil
+	}
+
+	return flags, "", false, false, nil
+}
+
+// allRegistered reports whether every rune in s names a registered short option.
+func allRegistered(s string, isRegistered func(name string) bool) bool {
+	for _, r := range s {
+		if !isRegistered(string(r)) {
+			return false
+		}
+	}
+	return true
+}
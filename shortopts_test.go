@@ -0,0 +1,92 @@
+package main
+
+import "testing"
+
+func registeredSet(names ...string) func(string) bool {
+	set := map[string]bool{}
+	for _, n := range names {
+		set[n] = true
+	}
+	return func(name string) bool { return set[name] }
+}
+
+func TestSplitShortOptionsBundledBooleans(t *testing.T) {
+	flags, _, hasValue, needsNext, err := splitShortOptions("la", registeredSet("l", "a"), registeredSet())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hasValue || needsNext {
+		t.Fatalf("expected a plain boolean bundle, got hasValue=%v needsNext=%v", hasValue, needsNext)
+	}
+	if want := []string{"l", "a"}; !equalStrings(flags, want) {
+		t.Fatalf("flags = %v, want %v", flags, want)
+	}
+}
+
+func TestSplitShortOptionsConcatenatedValue(t *testing.T) {
+	flags, value, hasValue, needsNext, err := splitShortOptions("p8080", registeredSet("p"), registeredSet("p"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasValue || needsNext || value != "8080" {
+		t.Fatalf("got value=%q hasValue=%v needsNext=%v, want value=8080 hasValue=true needsNext=false", value, hasValue, needsNext)
+	}
+	if want := []string{"p"}; !equalStrings(flags, want) {
+		t.Fatalf("flags = %v, want %v", flags, want)
+	}
+}
+
+func TestSplitShortOptionsEqualsValue(t *testing.T) {
+	_, value, hasValue, needsNext, err := splitShortOptions("p=80", registeredSet("p"), registeredSet("p"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasValue || needsNext || value != "80" {
+		t.Fatalf("got value=%q hasValue=%v needsNext=%v, want value=80 hasValue=true needsNext=false", value, hasValue, needsNext)
+	}
+}
+
+func TestSplitShortOptionsNextArgValue(t *testing.T) {
+	flags, _, hasValue, needsNext, err := splitShortOptions("p", registeredSet("p"), registeredSet("p"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hasValue || !needsNext {
+		t.Fatalf("got hasValue=%v needsNext=%v, want hasValue=false needsNext=true", hasValue, needsNext)
+	}
+	if want := []string{"p"}; !equalStrings(flags, want) {
+		t.Fatalf("flags = %v, want %v", flags, want)
+	}
+}
+
+func TestSplitShortOptionsBundledBooleansThenValue(t *testing.T) {
+	flags, _, hasValue, needsNext, err := splitShortOptions("lap", registeredSet("l", "a", "p"), registeredSet("p"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hasValue || !needsNext {
+		t.Fatalf("got hasValue=%v needsNext=%v, want hasValue=false needsNext=true", hasValue, needsNext)
+	}
+	if want := []string{"l", "a", "p"}; !equalStrings(flags, want) {
+		t.Fatalf("flags = %v, want %v", flags, want)
+	}
+}
+
+func TestSplitShortOptionsAmbiguousBundleErrors(t *testing.T) {
+	_, _, _, _, err := splitShortOptions("pla", registeredSet("p", "l", "a"), registeredSet("p"))
+	if err == nil {
+		t.Fatalf("expected an error for an ambiguous value-taking short bundle, got none")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
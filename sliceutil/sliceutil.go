@@ -0,0 +1,103 @@
+// Package sliceutil provides small, generic slice helpers. It replaces the hand-rolled,
+// []interface{}-based First/Last that used to live in godash.go (First didn't even compile: it
+// declared no return type but returned array[0]).
+package sliceutil
+
+// First returns the first element of s and true, or the zero value and false if s is empty.
+func First[T any](s []T) (T, bool) {
+	var zero T
+	if len(s) == 0 {
+		return zero, false
+	}
+	return s[0], true
+}
+
+// Last returns the last element of s and true, or the zero value and false if s is empty.
+func Last[T any](s []T) (T, bool) {
+	var zero T
+	if len(s) == 0 {
+		return zero, false
+	}
+	return s[len(s)-1], true
+}
+
+// Map applies fn to every element of s and returns the results in a new slice of the same
+// length.
+func Map[T, U any](s []T, fn func(T) U) []U {
+	out := make([]U, len(s))
+	for i, v := range s {
+		out[i] = fn(v)
+	}
+	return out
+}
+
+// Filter returns the elements of s for which fn returns true, preserving order.
+func Filter[T any](s []T, fn func(T) bool) []T {
+	out := make([]T, 0, len(s))
+	for _, v := range s {
+		if fn(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Reduce folds s into a single value, starting from init and applying fn left to right.
+func Reduce[T, U any](s []T, init U, fn func(U, T) U) U {
+	acc := init
+	for _, v := range s {
+		acc = fn(acc, v)
+	}
+	return acc
+}
+
+// Chunk splits s into consecutive chunks of at most size elements each. It panics if size <= 0.
+func Chunk[T any](s []T, size int) [][]T {
+	if size <= 0 {
+		panic("sliceutil: Chunk size must be positive")
+	}
+
+	var chunks [][]T
+	for len(s) > 0 {
+		n := size
+		if n > len(s) {
+			n = len(s)
+		}
+		chunks = append(chunks, s[:n:n])
+		s = s[n:]
+	}
+
+	return chunks
+}
+
+// Uniq returns the elements of s in order, with every element after its first occurrence
+// (by ==) removed.
+func Uniq[T comparable](s []T) []T {
+	seen := make(map[T]struct{}, len(s))
+	out := make([]T, 0, len(s))
+
+	for _, v := range s {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+
+	return out
+}
+
+// Flatten concatenates every slice in s into a single slice, in order.
+func Flatten[T any](s [][]T) []T {
+	var total int
+	for _, v := range s {
+		total += len(v)
+	}
+
+	out := make([]T, 0, total)
+	for _, v := range s {
+		out = append(out, v...)
+	}
+
+	return out
+}
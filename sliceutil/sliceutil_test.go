@@ -0,0 +1,88 @@
+package sliceutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFirst(t *testing.T) {
+	if v, ok := First([]int{1, 2, 3}); !ok || v != 1 {
+		t.Errorf("First([1,2,3]) = %v, %v; want 1, true", v, ok)
+	}
+
+	if _, ok := First([]int{}); ok {
+		t.Errorf("First([]) ok = true; want false")
+	}
+}
+
+func TestLast(t *testing.T) {
+	if v, ok := Last([]int{1, 2, 3}); !ok || v != 3 {
+		t.Errorf("Last([1,2,3]) = %v, %v; want 3, true", v, ok)
+	}
+
+	if _, ok := Last([]int{}); ok {
+		t.Errorf("Last([]) ok = true; want false")
+	}
+}
+
+func TestMap(t *testing.T) {
+	got := Map([]int{1, 2, 3}, func(v int) int { return v * 2 })
+	want := []int{2, 4, 6}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() = %v; want %v", got, want)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	got := Filter([]int{1, 2, 3, 4}, func(v int) bool { return v%2 == 0 })
+	want := []int{2, 4}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter() = %v; want %v", got, want)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	got := Reduce([]int{1, 2, 3, 4}, 0, func(acc, v int) int { return acc + v })
+	if got != 10 {
+		t.Errorf("Reduce() = %d; want 10", got)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	got := Chunk([]int{1, 2, 3, 4, 5}, 2)
+	want := [][]int{{1, 2}, {3, 4}, {5}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Chunk() = %v; want %v", got, want)
+	}
+}
+
+func TestChunkPanicsOnNonPositiveSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Chunk(s, 0) did not panic")
+		}
+	}()
+
+	Chunk([]int{1, 2}, 0)
+}
+
+func TestUniq(t *testing.T) {
+	got := Uniq([]int{1, 2, 2, 3, 1})
+	want := []int{1, 2, 3}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Uniq() = %v; want %v", got, want)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	got := Flatten([][]int{{1, 2}, {3}, {4, 5, 6}})
+	want := []int{1, 2, 3, 4, 5, 6}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Flatten() = %v; want %v", got, want)
+	}
+}